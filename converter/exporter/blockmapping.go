@@ -0,0 +1,199 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/NovelCraft/MinecraftLevelExporter/logger"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// BlockMappingJSONSchema is the json schema for a block mapping file.
+const BlockMappingJSONSchema string = `
+{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"type": "object",
+	"properties": {
+		"_default": { "type": "integer" },
+		"_missing": { "type": "integer" },
+		"rules": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"pattern": { "type": "string" },
+					"regex": { "type": "boolean" },
+					"states": { "type": "object" },
+					"id": { "type": "integer" }
+				},
+				"required": ["pattern", "id"]
+			}
+		}
+	},
+	"required": ["rules"]
+}
+`
+
+// BlockRule maps block names, optionally narrowed by blockstates, to a block
+// id. Rules are evaluated top-to-bottom by BlockMapping.Resolve; the first
+// matching rule wins.
+type BlockRule struct {
+	// Pattern is matched against a block's name. It is a glob (as understood
+	// by path.Match, e.g. "minecraft:*_log") unless Regex is set.
+	Pattern string `json:"pattern"`
+	// Regex makes Pattern a regular expression instead of a glob.
+	Regex bool `json:"regex,omitempty"`
+	// States, if non-empty, must all match the block's blockstates (compared
+	// as strings) for the rule to apply.
+	States map[string]string `json:"states,omitempty"`
+	Id     int               `json:"id"`
+
+	compiled *regexp.Regexp
+}
+
+// matches reports whether the rule applies to a block with the given name
+// and blockstates.
+func (r *BlockRule) matches(name string, states map[string]interface{}) bool {
+	if r.Regex {
+		if !r.compiled.MatchString(name) {
+			return false
+		}
+	} else if ok, _ := path.Match(r.Pattern, name); !ok {
+		return false
+	}
+
+	for key, want := range r.States {
+		got, ok := states[key]
+		if !ok || fmt.Sprintf("%v", got) != want {
+			return false
+		}
+	}
+
+	return true
+}
+
+// BlockMapping is an ordered set of BlockRule values plus the fallback ids
+// that replace the old flat blockDict's DefaultBlockId/OutOfRangeBlockId.
+type BlockMapping struct {
+	Rules     []BlockRule
+	DefaultId int
+	MissingId int
+
+	unmatched      map[string]bool
+	unmatchedOrder []string
+}
+
+// LoadBlockMapping reads the block mapping JSON file at path, validates it
+// against BlockMappingJSONSchema, compiles every regex rule, and unmarshals
+// it into a BlockMapping. A mapping file with no explicit "_default" or
+// "_missing" key falls back to DefaultBlockId or OutOfRangeBlockId.
+func LoadBlockMapping(mappingPath string) (*BlockMapping, error) {
+	jsonContent, err := os.ReadFile(mappingPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block mapping: %w", err)
+	}
+
+	schemaLoader := gojsonschema.NewStringLoader(BlockMappingJSONSchema)
+	documentLoader := gojsonschema.NewBytesLoader(jsonContent)
+
+	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate block mapping: %w", err)
+	}
+
+	if !result.Valid() {
+		return nil, fmt.Errorf("block mapping is not valid")
+	}
+
+	var file struct {
+		Rules     []BlockRule `json:"rules"`
+		DefaultId *int        `json:"_default"`
+		MissingId *int        `json:"_missing"`
+	}
+	if err := json.Unmarshal(jsonContent, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse block mapping: %w", err)
+	}
+
+	for i := range file.Rules {
+		rule := &file.Rules[i]
+		if rule.Regex {
+			compiled, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex pattern %q: %w", rule.Pattern, err)
+			}
+			rule.compiled = compiled
+		}
+	}
+
+	mapping := &BlockMapping{
+		Rules:     file.Rules,
+		DefaultId: DefaultBlockId,
+		MissingId: OutOfRangeBlockId,
+		unmatched: make(map[string]bool),
+	}
+	if file.DefaultId != nil {
+		mapping.DefaultId = *file.DefaultId
+	}
+	if file.MissingId != nil {
+		mapping.MissingId = *file.MissingId
+	}
+
+	return mapping, nil
+}
+
+// Resolve returns the block id for a block with the given name and
+// blockstates, evaluating the rules top-to-bottom and falling back to
+// DefaultId. The first time a name goes unmatched, it is logged via
+// logger.Warning; Unmatched reports every name this happened for.
+func (m *BlockMapping) Resolve(name string, states map[string]interface{}) int {
+	for _, rule := range m.Rules {
+		if rule.matches(name, states) {
+			return rule.Id
+		}
+	}
+
+	if !m.unmatched[name] {
+		m.unmatched[name] = true
+		m.unmatchedOrder = append(m.unmatchedOrder, name)
+		logger.Warning("no block mapping rule matched %q, using the default id", name)
+	}
+
+	return m.DefaultId
+}
+
+// Unmatched returns every block name Resolve fell back to DefaultId for, in
+// the order each was first encountered. Intended for a post-run summary
+// report.
+func (m *BlockMapping) Unmatched() []string {
+	names := make([]string, len(m.unmatchedOrder))
+	copy(names, m.unmatchedOrder)
+	return names
+}
+
+// Invert returns the ids of this mapping's unambiguous, exact-name rules
+// (no glob/regex pattern and no blockstate constraints), mapped back to
+// their name. The first such rule for a given id wins; rules that match a
+// whole family of names (globs, regexes, or blockstate-scoped rules) have
+// no single name to invert to and are skipped.
+func (m *BlockMapping) Invert() map[int]string {
+	inverted := make(map[int]string)
+	for _, rule := range m.Rules {
+		if rule.Regex || len(rule.States) > 0 || !isExactBlockName(rule.Pattern) {
+			continue
+		}
+		if _, exists := inverted[rule.Id]; !exists {
+			inverted[rule.Id] = rule.Pattern
+		}
+	}
+	return inverted
+}
+
+// isExactBlockName reports whether pattern is a literal block name rather
+// than a glob, i.e. contains none of path.Match's special characters.
+func isExactBlockName(pattern string) bool {
+	return !strings.ContainsAny(pattern, "*?[")
+}
@@ -0,0 +1,136 @@
+package exporter
+
+import "math"
+
+// Entity is an entity placed in the world, translated into the same
+// section-relative coordinate space ConvertToSections uses for blocks.
+type Entity struct {
+	Id       int                    `json:"id"`
+	SectionX int                    `json:"section_x"`
+	SectionY int                    `json:"section_y"`
+	SectionZ int                    `json:"section_z"`
+	Pos      [3]float64             `json:"pos"`
+	Rotation [2]float64             `json:"rotation,omitempty"`
+	Name     string                 `json:"name,omitempty"`
+	Nbt      map[string]interface{} `json:"nbt,omitempty"`
+}
+
+// Player is a player placed in the world, translated into the same
+// section-relative coordinate space ConvertToSections uses for blocks.
+type Player struct {
+	UUID      string          `json:"uuid"`
+	SectionX  int             `json:"section_x"`
+	SectionY  int             `json:"section_y"`
+	SectionZ  int             `json:"section_z"`
+	Pos       [3]float64      `json:"pos"`
+	Inventory []InventorySlot `json:"inventory,omitempty"`
+}
+
+// InventorySlot is a single item stack in a Player's inventory.
+type InventorySlot struct {
+	Slot  int    `json:"slot"`
+	Id    string `json:"id"`
+	Count int    `json:"count"`
+}
+
+// ConvertEntities converts the optional "entities" array of the input map
+// into Entity values, translating each entity id through entityDict and
+// falling back to defaultEntityId for names with no entry. It returns an
+// empty slice if the input map has no entities.
+func ConvertEntities(inputMap map[string]interface{}, entityDict map[string]int, defaultEntityId int) []Entity {
+	raw, _ := inputMap["entities"].([]interface{})
+
+	entities := make([]Entity, 0, len(raw))
+	for _, e := range raw {
+		entityMap := e.(map[string]interface{})
+
+		entityId, ok := entityDict[entityMap["id"].(string)]
+		if !ok {
+			entityId = defaultEntityId
+		}
+
+		sectionX, sectionY, sectionZ, pos := sectionRelativePos(entityMap["Pos"].([]interface{}))
+
+		entity := Entity{
+			Id:       entityId,
+			SectionX: sectionX,
+			SectionY: sectionY,
+			SectionZ: sectionZ,
+			Pos:      pos,
+		}
+
+		if rotation, ok := entityMap["Rotation"].([]interface{}); ok {
+			entity.Rotation = [2]float64{rotation[0].(float64), rotation[1].(float64)}
+		}
+
+		if customName, ok := entityMap["CustomName"].(string); ok {
+			entity.Name = customName
+		}
+
+		if nbt, ok := entityMap["Nbt"].(map[string]interface{}); ok {
+			entity.Nbt = nbt
+		}
+
+		entities = append(entities, entity)
+	}
+
+	return entities
+}
+
+// ConvertPlayers converts the optional "players" array of the input map
+// into Player values. It returns an empty slice if the input map has no
+// players.
+func ConvertPlayers(inputMap map[string]interface{}) []Player {
+	raw, _ := inputMap["players"].([]interface{})
+
+	players := make([]Player, 0, len(raw))
+	for _, p := range raw {
+		playerMap := p.(map[string]interface{})
+
+		sectionX, sectionY, sectionZ, pos := sectionRelativePos(playerMap["Pos"].([]interface{}))
+
+		player := Player{
+			UUID:     playerMap["UUID"].(string),
+			SectionX: sectionX,
+			SectionY: sectionY,
+			SectionZ: sectionZ,
+			Pos:      pos,
+		}
+
+		if inventory, ok := playerMap["Inventory"].([]interface{}); ok {
+			for _, slot := range inventory {
+				slotMap := slot.(map[string]interface{})
+				player.Inventory = append(player.Inventory, InventorySlot{
+					Slot:  int(slotMap["Slot"].(float64)),
+					Id:    slotMap["id"].(string),
+					Count: int(slotMap["Count"].(float64)),
+				})
+			}
+		}
+
+		players = append(players, player)
+	}
+
+	return players
+}
+
+// sectionRelativePos splits a raw [x, y, z] position into the origin of the
+// 16x16x16 section that contains it and the position relative to that
+// origin, matching the section layout ConvertToSections produces.
+func sectionRelativePos(rawPos []interface{}) (sectionX, sectionY, sectionZ int, pos [3]float64) {
+	x, y, z := rawPos[0].(float64), rawPos[1].(float64), rawPos[2].(float64)
+
+	sectionX = sectionOrigin(x)
+	sectionY = sectionOrigin(y)
+	sectionZ = sectionOrigin(z)
+
+	pos = [3]float64{x - float64(sectionX), y - float64(sectionY), z - float64(sectionZ)}
+	return
+}
+
+// sectionOrigin floors coord to the nearest multiple of 16, rather than
+// truncating toward zero, so negative coordinates land in the section that
+// actually contains them.
+func sectionOrigin(coord float64) int {
+	return int(math.Floor(coord/16)) * 16
+}
@@ -0,0 +1,411 @@
+// Package exporter holds the conversion pipeline shared by every CLI
+// subcommand: reading and validating structure JSON, and turning it into
+// the section-based level data this tool emits.
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Section represents a section of a Minecraft world. It contains the x, y, z
+// coordinates of the section and a 3D array of blocks. Blocks is int32,
+// rather than int, to keep a section's backing array compact; those arrays
+// are reused through sectionBlocksPool.
+type Section struct {
+	X      int     `json:"x"`
+	Y      int     `json:"y"`
+	Z      int     `json:"z"`
+	Blocks []int32 `json:"blocks"`
+}
+
+type Size struct {
+	X int
+	Y int
+	Z int
+}
+
+// InputJSONSchema is the json schema for the input json file: an object with
+// a 3-integer size, a structure of block indices and a block palette, and
+// optional entities and players arrays.
+const InputJSONSchema string = `
+{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"type": "object",
+	"properties": {
+		"size": {
+			"type": "array",
+			"items": {
+				"type": "integer",
+				"minimum": 1
+			},
+			"minItems": 3,
+			"maxItems": 3
+		},
+		"structure": {
+			"type": "object",
+			"properties": {
+				"block_indices": {
+					"type": "array",
+					"items": {
+						"type": "array",
+						"items": {
+							"type": "integer"
+						},
+						"minItems": 1
+					},
+					"minItems": 1
+				},
+				"palette": {
+					"type": "object",
+					"properties": {
+						"default": {
+							"type": "object",
+							"properties": {
+								"block_palette": {
+									"type": "array",
+									"items": {
+										"type": "object",
+										"properties": {
+											"name": {
+												"type": "string"
+											}
+										},
+										"required": ["name"]
+									},
+									"minItems": 1
+								}
+							},
+							"required": ["block_palette"]
+						}
+					},
+					"required": ["default"]
+				}
+			},
+			"required": ["block_indices", "palette"]
+		},
+		"entities": ` + entitiesJSONSchemaFragment + `,
+		"players": ` + playersJSONSchemaFragment + `
+	},
+	"required": ["size", "structure"]
+}
+`
+
+// entitiesJSONSchemaFragment describes the optional top-level "entities"
+// array: compound entity tags with an id, position, rotation, an optional
+// custom name, and an arbitrary NBT payload.
+const entitiesJSONSchemaFragment string = `
+{
+	"type": "array",
+	"items": {
+		"type": "object",
+		"properties": {
+			"id": {
+				"type": "string",
+				"pattern": "^minecraft:\\w+$"
+			},
+			"Pos": {
+				"type": "array",
+				"items": { "type": "number" },
+				"minItems": 3,
+				"maxItems": 3
+			},
+			"Rotation": {
+				"type": "array",
+				"items": { "type": "number" },
+				"minItems": 2,
+				"maxItems": 2
+			},
+			"CustomName": {
+				"type": "string"
+			},
+			"Nbt": {
+				"type": "object"
+			}
+		},
+		"required": ["id", "Pos"]
+	}
+}
+`
+
+// playersJSONSchemaFragment describes the optional top-level "players"
+// array: a UUID, a position, and an inventory of item stacks.
+const playersJSONSchemaFragment string = `
+{
+	"type": "array",
+	"items": {
+		"type": "object",
+		"properties": {
+			"UUID": {
+				"type": "string"
+			},
+			"Pos": {
+				"type": "array",
+				"items": { "type": "number" },
+				"minItems": 3,
+				"maxItems": 3
+			},
+			"Inventory": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {
+						"Slot": { "type": "integer" },
+						"id": {
+							"type": "string",
+							"pattern": "^minecraft:\\w+$"
+						},
+						"Count": { "type": "integer" }
+					},
+					"required": ["Slot", "id", "Count"]
+				}
+			}
+		},
+		"required": ["UUID", "Pos"]
+	}
+}
+`
+
+// EntityDictJSONSchema is the json schema for the entity dictionary file.
+const EntityDictJSONSchema string = `
+{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"type": "object",
+	"patternProperties": {
+		"^minecraft:\\w+$": {
+			"type": "integer"
+		}
+	}
+}
+`
+
+const DefaultBlockId = 0
+const OutOfRangeBlockId = -1
+const DefaultEntityId = -1
+
+// LoadInput reads the structure JSON file at path, validates it against
+// InputJSONSchema and unmarshals it into a map.
+func LoadInput(path string) (map[string]interface{}, error) {
+	jsonContent, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input file: %w", err)
+	}
+
+	schemaLoader := gojsonschema.NewStringLoader(InputJSONSchema)
+	documentLoader := gojsonschema.NewBytesLoader(jsonContent)
+
+	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate input file: %w", err)
+	}
+
+	if !result.Valid() {
+		return nil, fmt.Errorf("input file is not valid")
+	}
+
+	var inputMap map[string]interface{}
+	if err := json.Unmarshal(jsonContent, &inputMap); err != nil {
+		return nil, fmt.Errorf("failed to parse input file: %w", err)
+	}
+
+	return inputMap, nil
+}
+
+// LoadEntityDict reads the entity dictionary JSON file at path, validates it
+// against EntityDictJSONSchema and unmarshals it into a map.
+func LoadEntityDict(path string) (map[string]int, error) {
+	jsonContent, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read entity dictionary: %w", err)
+	}
+
+	schemaLoader := gojsonschema.NewStringLoader(EntityDictJSONSchema)
+	documentLoader := gojsonschema.NewBytesLoader(jsonContent)
+
+	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate entity dictionary: %w", err)
+	}
+
+	if !result.Valid() {
+		return nil, fmt.Errorf("entity dictionary is not valid")
+	}
+
+	var entityDict map[string]int
+	if err := json.Unmarshal(jsonContent, &entityDict); err != nil {
+		return nil, fmt.Errorf("failed to parse entity dictionary: %w", err)
+	}
+
+	return entityDict, nil
+}
+
+// ValidateInput checks that the first block layer holds exactly size X*Y*Z
+// indices and that the palette has exactly one entry per index up to the
+// highest index used.
+func ValidateInput(inputMap map[string]interface{}) bool {
+	expectedBlockCount := int(inputMap["size"].([]interface{})[0].(float64) * inputMap["size"].([]interface{})[1].(float64) *
+		inputMap["size"].([]interface{})[2].(float64))
+
+	blocks := inputMap["structure"].(map[string]interface{})["block_indices"].([]interface{})[0].([]interface{})
+
+	blockCount := len(blocks)
+
+	// Check if the number of blocks is correct.
+	if blockCount != expectedBlockCount {
+		return false
+	}
+
+	// Get the number of different block types.
+	var expectedBlockTypeCount int = 0
+	for _, e := range blocks {
+		num := int(e.(float64))
+		if num > expectedBlockTypeCount {
+			expectedBlockTypeCount = num
+		}
+	}
+	expectedBlockTypeCount++ // Add 1 because the block count starts at 0.
+
+	blockTypes := inputMap["structure"].(map[string]interface{})["palette"].(map[string]interface{})["default"].(map[string]interface{})["block_palette"].([]interface{})
+	blockTypeCount := len(blockTypes)
+
+	// Check if the number of block types is correct.
+	if blockTypeCount != expectedBlockTypeCount {
+		return false
+	} else {
+		return true
+	}
+}
+
+// BlockPaletteEntry is a single entry of a structure's block palette: the
+// block's name and, for blockstate-aware mappings, its states compound.
+type BlockPaletteEntry struct {
+	Name   string
+	States map[string]interface{}
+}
+
+// blockPalette reads the structure's block_palette into BlockPaletteEntry
+// values, in palette order.
+func blockPalette(inputMap map[string]interface{}) []BlockPaletteEntry {
+	blockTypes := inputMap["structure"].(map[string]interface{})["palette"].(map[string]interface{})["default"].(map[string]interface{})["block_palette"].([]interface{})
+
+	entries := make([]BlockPaletteEntry, 0, len(blockTypes))
+	for _, blockType := range blockTypes {
+		blockTypeMap := blockType.(map[string]interface{})
+		entry := BlockPaletteEntry{Name: blockTypeMap["name"].(string)}
+		if states, ok := blockTypeMap["states"].(map[string]interface{}); ok {
+			entry.States = states
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// inputSize reads the input map's "size" property.
+func inputSize(inputMap map[string]interface{}) Size {
+	return Size{
+		X: int(inputMap["size"].([]interface{})[0].(float64)),
+		Y: int(inputMap["size"].([]interface{})[1].(float64)),
+		Z: int(inputMap["size"].([]interface{})[2].(float64)),
+	}
+}
+
+// translateBlocks maps every raw block index in the input to a block id,
+// through mapping, once up front. The result is a compact []int32 rather
+// than []int so large worlds don't pay int's full width per block.
+//
+// A negative index (e.g. a .mcstructure cell that is structure-void in every
+// layer) has no palette entry; it is mapped straight to mapping.MissingId
+// rather than indexed into the palette.
+func translateBlocks(inputMap map[string]interface{}, mapping *BlockMapping) []int32 {
+	rawBlocks := inputMap["structure"].(map[string]interface{})["block_indices"].([]interface{})[0].([]interface{})
+	palette := blockPalette(inputMap)
+
+	blocks := make([]int32, len(rawBlocks))
+	for i, blockIndice := range rawBlocks {
+		index := int(blockIndice.(float64))
+		if index < 0 || index >= len(palette) {
+			blocks[i] = int32(mapping.MissingId)
+			continue
+		}
+		entry := palette[index]
+		blocks[i] = int32(mapping.Resolve(entry.Name, entry.States))
+	}
+
+	return blocks
+}
+
+// ConvertToSections converts the input map to an array of sections, mapping
+// every block through mapping. It materialises every section in memory; for
+// large worlds, prefer WriteLevelData, which streams sections to disk
+// instead.
+func ConvertToSections(inputMap map[string]interface{}, mapping *BlockMapping) []Section {
+	size := inputSize(inputMap)
+	blocks := translateBlocks(inputMap, mapping)
+
+	sectionCount := Size{
+		X: (size.X + 15) / 16,
+		Y: (size.Y + 15) / 16,
+		Z: (size.Z + 15) / 16,
+	}
+
+	sections := make([]Section, 0)
+	for x := 0; x < sectionCount.X; x++ {
+		for y := 0; y < sectionCount.Y; y++ {
+			for z := 0; z < sectionCount.Z; z++ {
+				offset := x*16*size.X*size.Y + y*16*size.X + z*16
+
+				sectionBlocks := make([]int32, 4096)
+				for i := 0; i < 4096; i++ {
+					if i+offset >= len(blocks) {
+						sectionBlocks[i] = int32(mapping.MissingId)
+					} else {
+						sectionBlocks[i] = blocks[i+offset]
+					}
+				}
+
+				section := Section{
+					X:      x * 16,
+					Y:      y * 16,
+					Z:      z * 16,
+					Blocks: sectionBlocks,
+				}
+
+				sections = append(sections, section)
+			}
+		}
+	}
+
+	return sections
+}
+
+// UniqueBlockNames returns the distinct block names referenced by the
+// input's palette, and the subset of those names that have no rule in
+// mapping matching both their name and their blockstates. Each name is
+// checked only against the blockstates of its first palette entry.
+func UniqueBlockNames(inputMap map[string]interface{}, mapping *BlockMapping) (names []string, missing []string) {
+	seen := make(map[string]bool)
+	for _, entry := range blockPalette(inputMap) {
+		if seen[entry.Name] {
+			continue
+		}
+		seen[entry.Name] = true
+		names = append(names, entry.Name)
+
+		matched := false
+		for _, rule := range mapping.Rules {
+			if rule.matches(entry.Name, entry.States) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			missing = append(missing, entry.Name)
+		}
+	}
+
+	return names, missing
+}
@@ -0,0 +1,117 @@
+package exporter
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+// mcStructure mirrors the top-level compound of a Bedrock Edition
+// .mcstructure file, only keeping the fields this tool needs.
+type mcStructure struct {
+	Size      []int32 `nbt:"size"`
+	Structure struct {
+		BlockIndices [][]int32 `nbt:"block_indices"`
+		Palette      struct {
+			Default struct {
+				BlockPalette []struct {
+					Name   string                 `nbt:"name"`
+					States map[string]interface{} `nbt:"states"`
+				} `nbt:"block_palette"`
+			} `nbt:"default"`
+		} `nbt:"palette"`
+	} `nbt:"structure"`
+}
+
+// AllLayers selects every block layer of a .mcstructure file, collapsing
+// them into one, instead of a single layer.
+const AllLayers = -1
+
+// LoadMCStructureInput reads a Bedrock Edition .mcstructure file at path and
+// translates it into the same map[string]interface{} representation the
+// JSON structure format produces, so it can be passed to ValidateInput and
+// ConvertToSections unchanged.
+//
+// layer selects which of the two block layers .mcstructure files store to
+// use. Pass AllLayers to collapse both layers into one, preferring the
+// second (waterlogging/overlay) layer wherever the first layer has no block
+// placed (index -1).
+func LoadMCStructureInput(path string, layer int) (map[string]interface{}, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mcstructure file: %w", err)
+	}
+
+	var structure mcStructure
+	if err := nbt.UnmarshalEncoding(raw, &structure, nbt.LittleEndian); err != nil {
+		return nil, fmt.Errorf("failed to decode mcstructure file: %w", err)
+	}
+
+	if len(structure.Structure.BlockIndices) == 0 {
+		return nil, fmt.Errorf("mcstructure file has no block layers")
+	}
+
+	indices, err := collapseLayers(structure.Structure.BlockIndices, layer)
+	if err != nil {
+		return nil, err
+	}
+
+	size := make([]interface{}, len(structure.Size))
+	for i, s := range structure.Size {
+		size[i] = float64(s)
+	}
+
+	blockIndices := make([]interface{}, len(indices))
+	for i, index := range indices {
+		blockIndices[i] = float64(index)
+	}
+
+	palette := make([]interface{}, len(structure.Structure.Palette.Default.BlockPalette))
+	for i, block := range structure.Structure.Palette.Default.BlockPalette {
+		entry := map[string]interface{}{"name": block.Name}
+		if len(block.States) > 0 {
+			entry["states"] = block.States
+		}
+		palette[i] = entry
+	}
+
+	return map[string]interface{}{
+		"size": size,
+		"structure": map[string]interface{}{
+			"block_indices": []interface{}{blockIndices},
+			"palette": map[string]interface{}{
+				"default": map[string]interface{}{
+					"block_palette": palette,
+				},
+			},
+		},
+	}, nil
+}
+
+// collapseLayers merges a .mcstructure file's block layers into the single
+// flat layer ConvertToSections expects.
+func collapseLayers(layers [][]int32, layer int) ([]int32, error) {
+	if layer != AllLayers {
+		if layer < 0 || layer >= len(layers) {
+			return nil, fmt.Errorf("layer %d out of range, structure has %d layers", layer, len(layers))
+		}
+		return layers[layer], nil
+	}
+
+	if len(layers) == 1 {
+		return layers[0], nil
+	}
+
+	first, second := layers[0], layers[1]
+	collapsed := make([]int32, len(first))
+	for i, index := range first {
+		if index == -1 {
+			collapsed[i] = second[i]
+		} else {
+			collapsed[i] = index
+		}
+	}
+
+	return collapsed, nil
+}
@@ -0,0 +1,230 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/NovelCraft/MinecraftLevelExporter/logger"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// ReverseDictJSONSchema is the json schema for a reverse dictionary file,
+// used to resolve block ids LevelToStructure can't unambiguously invert
+// through the block mapping.
+const ReverseDictJSONSchema string = `
+{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"type": "object",
+	"patternProperties": {
+		"^-?[0-9]+$": {
+			"type": "string",
+			"pattern": "^minecraft:\\w+$"
+		}
+	}
+}
+`
+
+// UnknownBlockName is substituted for block ids LevelToStructure cannot
+// resolve to a name at all.
+const UnknownBlockName = "minecraft:unknown"
+
+// StructureVoidBlockName is substituted for cells that hold the mapping's
+// MissingId, or have no backing section in the level data, rather than an
+// UnknownBlockName entry.
+const StructureVoidBlockName = "minecraft:structure_void"
+
+// LoadLevelData reads the level data JSON file at path, written by the
+// export command, and unmarshals it into a map.
+func LoadLevelData(path string) (map[string]interface{}, error) {
+	jsonContent, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read level data file: %w", err)
+	}
+
+	var levelData map[string]interface{}
+	if err := json.Unmarshal(jsonContent, &levelData); err != nil {
+		return nil, fmt.Errorf("failed to parse level data file: %w", err)
+	}
+
+	if _, ok := levelData["sections"].([]interface{}); !ok {
+		return nil, fmt.Errorf("level data file has no sections")
+	}
+	if _, ok := levelData["size"].([]interface{}); !ok {
+		return nil, fmt.Errorf("level data file has no size")
+	}
+
+	return levelData, nil
+}
+
+// LoadReverseDict reads the reverse dictionary JSON file at path, validates
+// it against ReverseDictJSONSchema, and unmarshals it into a map keyed by
+// block id.
+func LoadReverseDict(path string) (map[int]string, error) {
+	jsonContent, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reverse dictionary: %w", err)
+	}
+
+	schemaLoader := gojsonschema.NewStringLoader(ReverseDictJSONSchema)
+	documentLoader := gojsonschema.NewBytesLoader(jsonContent)
+
+	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate reverse dictionary: %w", err)
+	}
+
+	if !result.Valid() {
+		return nil, fmt.Errorf("reverse dictionary is not valid")
+	}
+
+	var byString map[string]string
+	if err := json.Unmarshal(jsonContent, &byString); err != nil {
+		return nil, fmt.Errorf("failed to parse reverse dictionary: %w", err)
+	}
+
+	reverseDict := make(map[int]string, len(byString))
+	for idString, name := range byString {
+		id, err := strconv.Atoi(idString)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block id %q in reverse dictionary: %w", idString, err)
+		}
+		reverseDict[id] = name
+	}
+
+	return reverseDict, nil
+}
+
+// LevelToStructure reconstructs a structure-format map, compatible with
+// InputJSONSchema, from level data produced by WriteLevelData/
+// ConvertToSections. It rebuilds exactly the original, unpadded world size
+// recorded by WriteLevelData, rather than guessing a bounding box back from
+// section padding. Block ids are resolved back to names through reverseDict
+// first, falling back to mapping's exact-match rules, and finally to
+// UnknownBlockName for ids neither source explains.
+func LevelToStructure(levelData map[string]interface{}, mapping *BlockMapping, reverseDict map[int]string) (map[string]interface{}, error) {
+	rawSections, _ := levelData["sections"].([]interface{})
+	if len(rawSections) == 0 {
+		return nil, fmt.Errorf("level data has no sections")
+	}
+
+	size, err := parseSize(levelData)
+	if err != nil {
+		return nil, err
+	}
+
+	flat, err := rebuildBlocks(rawSections, size, mapping.MissingId)
+	if err != nil {
+		return nil, err
+	}
+
+	inverted := mapping.Invert()
+
+	paletteNames := make([]string, 0)
+	nameIndex := make(map[string]int)
+	warned := make(map[int]bool)
+	indices := make([]int, len(flat))
+
+	for i, id := range flat {
+		var name string
+		switch {
+		case id == mapping.MissingId:
+			// size is exactly the original world's, so this happens where a
+			// declared cell has no backing section at all, or where the export
+			// itself wrote MissingId for an input index with no palette entry
+			// (such as a .mcstructure structure-void cell); treat it as void
+			// rather than inventing an UnknownBlockName entry for it.
+			name = StructureVoidBlockName
+		default:
+			var ok bool
+			name, ok = reverseDict[id]
+			if !ok {
+				name, ok = inverted[id]
+			}
+			if !ok {
+				if !warned[id] {
+					warned[id] = true
+					logger.Warning("block id %d has no reverse mapping, using %s", id, UnknownBlockName)
+				}
+				name = UnknownBlockName
+			}
+		}
+
+		idx, ok := nameIndex[name]
+		if !ok {
+			idx = len(paletteNames)
+			paletteNames = append(paletteNames, name)
+			nameIndex[name] = idx
+		}
+		indices[i] = idx
+	}
+
+	blockPalette := make([]interface{}, len(paletteNames))
+	for i, name := range paletteNames {
+		blockPalette[i] = map[string]interface{}{"name": name}
+	}
+
+	return map[string]interface{}{
+		"size": []int{size.X, size.Y, size.Z},
+		"structure": map[string]interface{}{
+			"block_indices": [][]int{indices},
+			"palette": map[string]interface{}{
+				"default": map[string]interface{}{
+					"block_palette": blockPalette,
+				},
+			},
+		},
+	}, nil
+}
+
+// parseSize reads the level data's "size" property, written by
+// WriteLevelData, as the original, unpadded world size.
+func parseSize(levelData map[string]interface{}) (Size, error) {
+	rawSize, _ := levelData["size"].([]interface{})
+	if len(rawSize) != 3 {
+		return Size{}, fmt.Errorf("level data size must have 3 dimensions")
+	}
+
+	return Size{
+		X: int(rawSize[0].(float64)),
+		Y: int(rawSize[1].(float64)),
+		Z: int(rawSize[2].(float64)),
+	}, nil
+}
+
+// rebuildBlocks lays every section's blocks back into a single flat []int
+// covering exactly size, placing each section at the same offset
+// WriteLevelData/ConvertToSections computed to read it out: offset =
+// gx*16*size.X*size.Y + gy*16*size.X + gz*16, with the section's 4096
+// blocks written linearly from there. Reading size directly, rather than
+// guessing a bounding box back from section padding, means missingId
+// padding cells never enter the result at all.
+func rebuildBlocks(rawSections []interface{}, size Size, missingId int) ([]int, error) {
+	flat := make([]int, size.X*size.Y*size.Z)
+	for i := range flat {
+		flat[i] = missingId
+	}
+
+	for _, raw := range rawSections {
+		sectionMap, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("section is not an object")
+		}
+
+		gx := int(sectionMap["x"].(float64)) / 16
+		gy := int(sectionMap["y"].(float64)) / 16
+		gz := int(sectionMap["z"].(float64)) / 16
+		offset := gx*16*size.X*size.Y + gy*16*size.X + gz*16
+
+		rawBlocks := sectionMap["blocks"].([]interface{})
+		for i, b := range rawBlocks {
+			if offset+i >= len(flat) {
+				break
+			}
+			flat[offset+i] = int(b.(float64))
+		}
+	}
+
+	return flat, nil
+}
@@ -0,0 +1,161 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+)
+
+// sectionBlocksPool reuses the 4096-long []int32 backing arrays sections are
+// built from, so a large world doesn't allocate one per section.
+var sectionBlocksPool = sync.Pool{
+	New: func() interface{} {
+		return make([]int32, 4096)
+	},
+}
+
+// sectionResult pairs a Section with its position in the deterministic
+// x/y/z iteration order, so the writer can emit sections in that order even
+// though workers produce them out of order.
+type sectionResult struct {
+	seq     int
+	section Section
+}
+
+// WriteLevelData converts the input map to level data and streams it
+// straight to file as JSON, instead of building the whole document in
+// memory. Sections are produced by a pool of workers sized by
+// runtime.NumCPU(), each owning whole x/z columns of the world so they never
+// need to coordinate over shared state, and are written out through
+// json.Encoder as soon as they arrive in order. The original (unpadded)
+// world size is written alongside the sections so LevelToStructure can
+// recover it exactly instead of guessing it back from section padding.
+func WriteLevelData(inputMap map[string]interface{}, mapping *BlockMapping, entities []Entity, players []Player, file *os.File) error {
+	size := inputSize(inputMap)
+	blocks := translateBlocks(inputMap, mapping)
+
+	sectionCount := Size{
+		X: (size.X + 15) / 16,
+		Y: (size.Y + 15) / 16,
+		Z: (size.Z + 15) / 16,
+	}
+
+	results := make(chan sectionResult)
+	columns := make(chan [2]int)
+
+	var workers sync.WaitGroup
+	workerCount := runtime.NumCPU()
+	if workerCount < 1 {
+		workerCount = 1
+	}
+
+	for i := 0; i < workerCount; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for column := range columns {
+				x, z := column[0], column[1]
+				for y := 0; y < sectionCount.Y; y++ {
+					offset := x*16*size.X*size.Y + y*16*size.X + z*16
+
+					sectionBlocks := sectionBlocksPool.Get().([]int32)[:4096]
+					for i := 0; i < 4096; i++ {
+						if i+offset >= len(blocks) {
+							sectionBlocks[i] = int32(mapping.MissingId)
+						} else {
+							sectionBlocks[i] = blocks[i+offset]
+						}
+					}
+
+					seq := x*sectionCount.Y*sectionCount.Z + y*sectionCount.Z + z
+					results <- sectionResult{
+						seq: seq,
+						section: Section{
+							X:      x * 16,
+							Y:      y * 16,
+							Z:      z * 16,
+							Blocks: sectionBlocks,
+						},
+					}
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(columns)
+		for x := 0; x < sectionCount.X; x++ {
+			for z := 0; z < sectionCount.Z; z++ {
+				columns <- [2]int{x, z}
+			}
+		}
+	}()
+
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	header := fmt.Sprintf(`{"type":"level_data","size":[%d,%d,%d],"sections":[`, size.X, size.Y, size.Z)
+	if _, err := file.WriteString(header); err != nil {
+		return fmt.Errorf("failed to write level data header: %w", err)
+	}
+
+	encoder := json.NewEncoder(file)
+	totalSections := sectionCount.X * sectionCount.Y * sectionCount.Z
+	pending := make(map[int]Section, workerCount)
+	next := 0
+	written := 0
+
+	flushReady := func() error {
+		for {
+			section, ok := pending[next]
+			if !ok {
+				return nil
+			}
+			delete(pending, next)
+
+			if written > 0 {
+				if _, err := file.WriteString(","); err != nil {
+					return fmt.Errorf("failed to write level data: %w", err)
+				}
+			}
+			if err := encoder.Encode(section); err != nil {
+				return fmt.Errorf("failed to encode section: %w", err)
+			}
+
+			sectionBlocksPool.Put(section.Blocks)
+			written++
+			next++
+		}
+	}
+
+	for result := range results {
+		pending[result.seq] = result.section
+		if err := flushReady(); err != nil {
+			return err
+		}
+	}
+
+	if written != totalSections {
+		return fmt.Errorf("expected %d sections, wrote %d", totalSections, written)
+	}
+
+	entitiesJson, err := json.Marshal(entities)
+	if err != nil {
+		return fmt.Errorf("failed to convert entities to json: %w", err)
+	}
+
+	playersJson, err := json.Marshal(players)
+	if err != nil {
+		return fmt.Errorf("failed to convert players to json: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(file, `],"entities":%s,"players":%s}`, entitiesJson, playersJson); err != nil {
+		return fmt.Errorf("failed to write level data footer: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/NovelCraft/MinecraftLevelExporter/exporter"
+	"github.com/NovelCraft/MinecraftLevelExporter/logger"
+	"github.com/urfave/cli/v2"
+)
+
+func main() {
+	app := &cli.App{
+		Name:  "MinecraftLevelExporter",
+		Usage: "convert structure dumps into level data",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "no-color",
+				Usage: "disable colored log output",
+			},
+		},
+		Before: func(ctx *cli.Context) error {
+			logger.SetColor(!ctx.Bool("no-color"))
+			return nil
+		},
+		Commands: []*cli.Command{
+			exportCommand,
+			validateCommand,
+			inspectCommand,
+			importCommand,
+		},
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+var inputOutputFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:     "input",
+		Aliases:  []string{"i"},
+		Usage:    "structure JSON file to read",
+		Required: true,
+	},
+	&cli.StringFlag{
+		Name:     "dict",
+		Aliases:  []string{"d"},
+		Usage:    "block mapping JSON file to read",
+		Required: true,
+	},
+	&cli.IntFlag{
+		Name:  "layer",
+		Value: exporter.AllLayers,
+		Usage: "for .mcstructure input, the block layer to use (default: collapse both layers)",
+	},
+}
+
+var exportCommand = &cli.Command{
+	Name:  "export",
+	Usage: "convert a structure file and dictionary into level data",
+	Flags: append(inputOutputFlags,
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "level data file to write (defaults to <input>.level.json)",
+		},
+		&cli.StringFlag{
+			Name:  "entity-dict",
+			Usage: "entity dictionary JSON file, to translate the optional \"entities\" array",
+		},
+	),
+	Action: func(ctx *cli.Context) error {
+		inputPath := ctx.String("input")
+
+		inputMap, mapping, err := loadInputAndDict(ctx)
+		if err != nil {
+			return err
+		}
+
+		if !exporter.ValidateInput(inputMap) {
+			return fmt.Errorf("input map is not valid")
+		}
+
+		entityDict := make(map[string]int)
+		if entityDictPath := ctx.String("entity-dict"); entityDictPath != "" {
+			entityDict, err = exporter.LoadEntityDict(entityDictPath)
+			if err != nil {
+				return err
+			}
+		}
+
+		entities := exporter.ConvertEntities(inputMap, entityDict, exporter.DefaultEntityId)
+		players := exporter.ConvertPlayers(inputMap)
+
+		outputPath := ctx.String("output")
+		if outputPath == "" {
+			outputPath = strings.TrimSuffix(strings.TrimSuffix(inputPath, ".json"), ".mcstructure") + ".level.json"
+		}
+
+		output, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to create level data file: %w", err)
+		}
+		defer output.Close()
+
+		if err := exporter.WriteLevelData(inputMap, mapping, entities, players, output); err != nil {
+			return fmt.Errorf("failed to write level data to file: %w", err)
+		}
+
+		if unmatched := mapping.Unmatched(); len(unmatched) > 0 {
+			logger.Warning("%d block names had no matching rule and used the default id:", len(unmatched))
+			for _, name := range unmatched {
+				logger.Warning("  %s", name)
+			}
+		}
+
+		logger.Info("Successfully converted input file to level data")
+		return nil
+	},
+}
+
+var validateCommand = &cli.Command{
+	Name:  "validate",
+	Usage: "run the schema and shape checks without producing output",
+	Flags: inputOutputFlags,
+	Action: func(ctx *cli.Context) error {
+		inputMap, _, err := loadInputAndDict(ctx)
+		if err != nil {
+			return err
+		}
+
+		if !exporter.ValidateInput(inputMap) {
+			return fmt.Errorf("input map is not valid")
+		}
+
+		logger.Info("Input file is valid")
+		return nil
+	},
+}
+
+var inspectCommand = &cli.Command{
+	Name:  "inspect",
+	Usage: "print size, section count, and block name coverage",
+	Flags: inputOutputFlags,
+	Action: func(ctx *cli.Context) error {
+		inputMap, mapping, err := loadInputAndDict(ctx)
+		if err != nil {
+			return err
+		}
+
+		size := exporter.Size{
+			X: int(inputMap["size"].([]interface{})[0].(float64)),
+			Y: int(inputMap["size"].([]interface{})[1].(float64)),
+			Z: int(inputMap["size"].([]interface{})[2].(float64)),
+		}
+		sectionCount := ((size.X + 15) / 16) * ((size.Y + 15) / 16) * ((size.Z + 15) / 16)
+
+		names, missing := exporter.UniqueBlockNames(inputMap, mapping)
+
+		logger.Info("Size: %dx%dx%d", size.X, size.Y, size.Z)
+		logger.Info("Sections: %d", sectionCount)
+		logger.Info("Unique block names: %d", len(names))
+		for _, name := range names {
+			logger.Info("  %s", name)
+		}
+
+		if len(missing) > 0 {
+			logger.Warning("%d block names have no matching mapping rule:", len(missing))
+			for _, name := range missing {
+				logger.Warning("  %s", name)
+			}
+		}
+
+		return nil
+	},
+}
+
+var importCommand = &cli.Command{
+	Name:  "import",
+	Usage: "reconstruct structure JSON from level data produced by export",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "input",
+			Aliases:  []string{"i"},
+			Usage:    "level data JSON file to read",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:     "dict",
+			Aliases:  []string{"d"},
+			Usage:    "block mapping JSON file used for the original export",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "reverse-dict",
+			Usage: "block id -> name JSON file, to resolve ids the mapping's rules can't invert unambiguously",
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "structure file to write (defaults to <input>.structure.json)",
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		inputPath := ctx.String("input")
+
+		levelData, err := exporter.LoadLevelData(inputPath)
+		if err != nil {
+			return err
+		}
+
+		mapping, err := exporter.LoadBlockMapping(ctx.String("dict"))
+		if err != nil {
+			return err
+		}
+
+		reverseDict := make(map[int]string)
+		if reverseDictPath := ctx.String("reverse-dict"); reverseDictPath != "" {
+			reverseDict, err = exporter.LoadReverseDict(reverseDictPath)
+			if err != nil {
+				return err
+			}
+		}
+
+		structure, err := exporter.LevelToStructure(levelData, mapping, reverseDict)
+		if err != nil {
+			return err
+		}
+
+		structureJson, err := json.Marshal(structure)
+		if err != nil {
+			return fmt.Errorf("failed to convert structure to json: %w", err)
+		}
+
+		outputPath := ctx.String("output")
+		if outputPath == "" {
+			outputPath = strings.TrimSuffix(inputPath, ".json") + ".structure.json"
+		}
+
+		if err := os.WriteFile(outputPath, structureJson, 0644); err != nil {
+			return fmt.Errorf("failed to write structure to file: %w", err)
+		}
+
+		logger.Info("Successfully reconstructed structure from level data")
+		return nil
+	},
+}
+
+// loadInputAndDict loads and validates the --input and --dict flags shared
+// by the export, validate and inspect subcommands. The input file may be either structure JSON or a
+// Bedrock Edition .mcstructure file, picked by its extension.
+func loadInputAndDict(ctx *cli.Context) (map[string]interface{}, *exporter.BlockMapping, error) {
+	inputPath := ctx.String("input")
+	dictPath := ctx.String("dict")
+
+	if !strings.HasSuffix(dictPath, ".json") {
+		return nil, nil, fmt.Errorf("dictionary file must be a JSON file")
+	}
+
+	var inputMap map[string]interface{}
+	var err error
+	switch {
+	case strings.HasSuffix(inputPath, ".json"):
+		inputMap, err = exporter.LoadInput(inputPath)
+	case strings.HasSuffix(inputPath, ".mcstructure"):
+		inputMap, err = exporter.LoadMCStructureInput(inputPath, ctx.Int("layer"))
+	default:
+		return nil, nil, fmt.Errorf("input file must be a .json or .mcstructure file")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mapping, err := exporter.LoadBlockMapping(dictPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return inputMap, mapping, nil
+}